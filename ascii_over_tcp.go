@@ -0,0 +1,67 @@
+package modbus
+
+import (
+	"log"
+	"net"
+	"net/url"
+	"time"
+)
+
+const (
+	// asciiOverTCPURLScheme is the URL scheme for tunneling Modbus ASCII
+	// frames over a TCP connection, e.g. to a serial terminal server.
+	asciiOverTCPURLScheme string = "asciiovertcp"
+	// asciiOverUDPURLScheme and asciiOverUDPAltURLScheme are the URL
+	// schemes for tunneling Modbus ASCII frames over UDP datagrams.
+	asciiOverUDPURLScheme    string = "asciiovertcp+udp"
+	asciiOverUDPAltURLScheme string = "asciioverudp"
+)
+
+// newASCIIOverTCPTransport dials the TCP endpoint described by u and wraps
+// the resulting net.Conn in an asciiTransport, the same way RTU-over-TCP
+// tunnels a net.Conn through rtuTransport. net.Conn already satisfies the
+// rtuLink interface, so no adapter is needed. speed 0 is passed down to
+// newASCIITransport so the t1/t35 serial timing is skipped, since there is
+// no UART on the other end of a terminal server link.
+func newASCIIOverTCPTransport(u *url.URL, timeout time.Duration, customLogger *log.Logger) (at *asciiTransport, err error) {
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		return
+	}
+
+	at = newASCIITransport(conn, u.Host, 0, timeout, customLogger)
+
+	return
+}
+
+// newASCIIOverUDPTransport dials the UDP endpoint described by u and wraps
+// the resulting net.Conn in an asciiTransport. See newASCIIOverTCPTransport
+// for the rationale behind passing speed 0.
+func newASCIIOverUDPTransport(u *url.URL, timeout time.Duration, customLogger *log.Logger) (at *asciiTransport, err error) {
+	conn, err := net.Dial("udp", u.Host)
+	if err != nil {
+		return
+	}
+
+	at = newASCIITransport(conn, u.Host, 0, timeout, customLogger)
+
+	return
+}
+
+// newASCIIOverTCPListener listens for incoming TCP connections on the
+// address described by u, for use by NewServer when serving the
+// asciiovertcp:// scheme.
+func newASCIIOverTCPListener(u *url.URL) (ln net.Listener, err error) {
+	ln, err = net.Listen("tcp", u.Host)
+
+	return
+}
+
+// newASCIIOverUDPListener opens a UDP socket on the address described by u,
+// for use by NewServer when serving the asciiovertcp+udp:// (aka
+// asciioverudp://) scheme.
+func newASCIIOverUDPListener(u *url.URL) (conn net.PacketConn, err error) {
+	conn, err = net.ListenPacket("udp", u.Host)
+
+	return
+}
@@ -0,0 +1,61 @@
+package modbus
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewASCIIOverTCPTransportNoSerialTiming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, aerr := ln.Accept()
+		if aerr == nil {
+			conn.Close()
+		}
+	}()
+
+	u, err := url.Parse("asciiovertcp://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	at, err := newASCIIOverTCPTransport(u, 200*time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("newASCIIOverTCPTransport() failed: %v", err)
+	}
+	defer at.Close()
+
+	if at.t1 != 0 || at.t35 != 0 {
+		t.Fatalf("expected no serial timing on a tunneled link, got t1=%v t35=%v", at.t1, at.t35)
+	}
+}
+
+func TestNewASCIIOverUDPTransportNoSerialTiming(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer pc.Close()
+
+	u, err := url.Parse("asciiovertcp+udp://" + pc.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	at, err := newASCIIOverUDPTransport(u, 200*time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("newASCIIOverUDPTransport() failed: %v", err)
+	}
+	defer at.Close()
+
+	if at.t1 != 0 || at.t35 != 0 {
+		t.Fatalf("expected no serial timing on a tunneled link, got t1=%v t35=%v", at.t1, at.t35)
+	}
+}
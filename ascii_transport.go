@@ -1,6 +1,7 @@
 package modbus
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"log"
@@ -19,26 +20,47 @@ type asciiTransport struct {
 	lastActivity time.Time
 	t35          time.Duration
 	t1           time.Duration
+	counters     *diagnosticCounters
+	tracer       TransportTracer
+}
+
+// SetDiagnosticCounters attaches the server-wide bus counters/event log to
+// this transport, so that readASCIIFrame can feed the FC 11/FC 12 data as it
+// detects LRC failures. Passing nil disables counting.
+func (at *asciiTransport) SetDiagnosticCounters(counters *diagnosticCounters) {
+	at.counters = counters
+}
+
+// SetTracer installs a TransportTracer on the transport, consulted around
+// every Write/Read and around frame assembly/decoding. Passing nil removes
+// any previously installed tracer.
+func (at *asciiTransport) SetTracer(tracer TransportTracer) {
+	at.tracer = tracer
 }
 
 // Returns a new ASCII transport.
+//
+// speed is the serial line speed in bps, and drives the t1 (character) and
+// t35 (inter-frame) timings that ExecuteRequest enforces on a real UART. When
+// the link isn't backed by a physical serial port (e.g. asciiovertcp:// and
+// asciiovertcp+udp:// tunnels), callers should pass speed 0: t1 and t35 are
+// then left at zero, which short-circuits the timing delays entirely since
+// they make no sense on a TCP/UDP link.
 func newASCIITransport(link rtuLink, addr string, speed uint, timeout time.Duration, customLogger *log.Logger) (at *asciiTransport) {
-	// default to 19200 if no speed is provided (e.g. when tunneling over TCP/UDP)
-	if speed == 0 {
-		speed = 19200
-	}
-
 	at = &asciiTransport{
 		logger:  newLogger(fmt.Sprintf("ascii-transport(%s)", addr), customLogger),
 		link:    link,
 		timeout: timeout,
-		t1:      serialCharTime(speed),
 	}
 
-	if speed >= 19200 {
-		at.t35 = 1750 * time.Microsecond
-	} else {
-		at.t35 = (serialCharTime(speed) * 35) / 10
+	if speed > 0 {
+		at.t1 = serialCharTime(speed)
+
+		if speed >= 19200 {
+			at.t35 = 1750 * time.Microsecond
+		} else {
+			at.t35 = (serialCharTime(speed) * 35) / 10
+		}
 	}
 
 	return
@@ -75,6 +97,7 @@ func (at *asciiTransport) ExecuteRequest(req *pdu) (res *pdu, err error) {
 	if err != nil {
 		return
 	}
+	at.traceTx(frame, req)
 
 	// estimate time on the wire; ASCII frames are text, so use char time * bytes written
 	at.lastActivity = ts.Add(time.Duration(n) * at.t1)
@@ -124,6 +147,7 @@ func (at *asciiTransport) WriteResponse(res *pdu) (err error) {
 	if err != nil {
 		return
 	}
+	at.traceTx(frame, res)
 
 	at.lastActivity = time.Now().Add(time.Duration(n) * at.t1)
 
@@ -136,6 +160,24 @@ func (at *asciiTransport) readASCIIFrame() (res *pdu, err error) {
 	var tmp []byte
 	var colon bool
 
+	defer func() {
+		if at.counters != nil {
+			switch err {
+			case nil:
+				at.counters.recordFrame()
+			case ErrBadLRC:
+				at.counters.recordCommError()
+			}
+		}
+
+		if at.tracer != nil {
+			at.tracer.OnFrameRx(DirRx, rxbuf, res, err)
+			if err != nil {
+				at.tracer.OnError(err)
+			}
+		}
+	}()
+
 	rxbuf = make([]byte, 0, maxASCIIFrameLength)
 	tmp = make([]byte, 1)
 
@@ -176,6 +218,12 @@ func (at *asciiTransport) readASCIIFrame() (res *pdu, err error) {
 		}
 	}
 
+	if at.tracer != nil {
+		if injected, ok := at.tracer.InjectRx(context.Background(), rxbuf); ok {
+			rxbuf = injected
+		}
+	}
+
 	if len(rxbuf) < 3 || rxbuf[len(rxbuf)-2] != '\r' {
 		err = ErrProtocolError
 		return
@@ -248,6 +296,14 @@ func (at *asciiTransport) assembleASCIIFrame(p *pdu) (frame []byte) {
 	return
 }
 
+// traceTx reports a frame that was just written to the link, if a tracer is
+// installed.
+func (at *asciiTransport) traceTx(raw []byte, p *pdu) {
+	if at.tracer != nil {
+		at.tracer.OnFrameTx(DirTx, raw, p)
+	}
+}
+
 func toHexUpper(b byte) (hexPair []byte) {
 	const table = "0123456789ABCDEF"
 
@@ -34,11 +34,28 @@ func TestNewClientASCII(t *testing.T) {
 	}
 }
 
-func TestNewClientASCIIOverTCPSchemeUnsupported(t *testing.T) {
-	_, err := NewClient(&ClientConfiguration{
+func TestNewClientASCIIOverTCP(t *testing.T) {
+	client, err := NewClient(&ClientConfiguration{
 		URL: "asciiovertcp://localhost:502",
 	})
-	if err == nil {
-		t.Fatalf("expected configuration error for ascii over tcp")
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if client.transportType != modbusASCIIOverTCP {
+		t.Fatalf("unexpected transport type: got %v, want %v", client.transportType, modbusASCIIOverTCP)
+	}
+}
+
+func TestNewClientASCIIOverUDP(t *testing.T) {
+	client, err := NewClient(&ClientConfiguration{
+		URL: "asciiovertcp+udp://localhost:502",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if client.transportType != modbusASCIIOverUDP {
+		t.Fatalf("unexpected transport type: got %v, want %v", client.transportType, modbusASCIIOverUDP)
 	}
 }
@@ -0,0 +1,187 @@
+package modbus
+
+import (
+	"sync"
+)
+
+// Modbus diagnostic and serial line function codes.
+const (
+	fcReadExceptionStatus uint8 = 0x07
+	fcDiagnostics         uint8 = 0x08
+	fcGetCommEventCounter uint8 = 0x0b
+	fcGetCommEventLog     uint8 = 0x0c
+	fcReportServerID      uint8 = 0x11
+)
+
+// FC 8 (Diagnostics) sub-function codes, as defined by the Modbus
+// application protocol spec.
+const (
+	diagSubReturnQueryData                    uint16 = 0x0000
+	diagSubRestartCommunicationsOption        uint16 = 0x0001
+	diagSubReturnDiagnosticRegister           uint16 = 0x0002
+	diagSubClearCountersAndDiagnosticRegister uint16 = 0x000a
+	diagSubReturnBusMessageCount              uint16 = 0x000b
+	diagSubReturnBusCommErrorCount            uint16 = 0x000c
+	diagSubReturnBusExceptionErrorCount       uint16 = 0x000d
+	diagSubReturnServerMessageCount           uint16 = 0x000e
+	diagSubReturnServerNoResponseCount        uint16 = 0x000f
+)
+
+// DiagnosticsRequest describes an incoming FC 8 (Diagnostics) request.
+type DiagnosticsRequest struct {
+	UnitId  uint8
+	SubFunc uint16
+	Data    []byte
+}
+
+// DiagnosticsHandler is implemented by server handlers that want to handle
+// FC 8 (Diagnostics) sub-functions themselves rather than relying on the
+// built-in defaults provided by defaultHandleDiagnostics.
+type DiagnosticsHandler interface {
+	HandleDiagnostics(req *DiagnosticsRequest) (res []byte, err error)
+}
+
+// CommEventCounterHandler is implemented by server handlers that want to
+// answer FC 11 (Get Comm Event Counter) themselves instead of relying on the
+// bus message counters maintained automatically by the server.
+type CommEventCounterHandler interface {
+	HandleGetCommEventCounter(unitID uint8) (status uint16, eventCount uint16, err error)
+}
+
+// CommEventLogHandler is implemented by server handlers that want to answer
+// FC 12 (Get Comm Event Log) themselves instead of relying on the event log
+// maintained automatically by the server.
+type CommEventLogHandler interface {
+	HandleGetCommEventLog(unitID uint8) (status uint16, eventCount uint16, messageCount uint16, events []byte, err error)
+}
+
+// ServerIDHandler is implemented by server handlers that want to answer
+// FC 17 (Report Server ID).
+type ServerIDHandler interface {
+	HandleReportServerID(unitID uint8) (serverID []byte, run bool, err error)
+}
+
+// ExceptionStatusHandler is implemented by server handlers that want to
+// answer FC 7 (Read Exception Status), typically by packing eight
+// commissioning-relevant coils/flags into a single status byte.
+type ExceptionStatusHandler interface {
+	HandleReadExceptionStatus(unitID uint8) (status uint8, err error)
+}
+
+// diagnosticCounters tracks the bus-level message/error counters and the
+// event log required by FC 11 (Get Comm Event Counter) and FC 12 (Get Comm
+// Event Log). A server owns one instance and shares it across every link it
+// serves; transports such as rtuTransport and asciiTransport increment it
+// directly as frames are read, since they're the ones that already detect
+// CRC/LRC failures.
+type diagnosticCounters struct {
+	mu                     sync.Mutex
+	busMessageCount        uint16
+	busCommErrorCount      uint16
+	busExceptionErrorCount uint16
+	serverMessageCount     uint16
+	serverNoResponseCount  uint16
+	eventCount             uint16
+	events                 []byte
+}
+
+// maxEventLogLength bounds the event log returned by FC 12, mirroring the
+// behavior of real PLCs which keep only the most recent events.
+const maxEventLogLength int = 64
+
+// recordFrame accounts for a successfully decoded frame.
+func (dc *diagnosticCounters) recordFrame() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.busMessageCount++
+	dc.serverMessageCount++
+}
+
+// recordCommError accounts for a frame that failed its checksum (CRC for
+// RTU, LRC for ASCII).
+func (dc *diagnosticCounters) recordCommError() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.busMessageCount++
+	dc.busCommErrorCount++
+}
+
+// recordException accounts for a request that was answered with a Modbus
+// exception response, and appends it to the event log.
+func (dc *diagnosticCounters) recordException(event byte) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.busExceptionErrorCount++
+	dc.appendEventLocked(event)
+}
+
+// recordEvent appends an arbitrary event byte to the log, e.g. when a
+// request couldn't be answered by this unit id (no response sent).
+func (dc *diagnosticCounters) recordEvent(event byte) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.appendEventLocked(event)
+}
+
+func (dc *diagnosticCounters) appendEventLocked(event byte) {
+	dc.eventCount++
+	dc.events = append([]byte{event}, dc.events...)
+	if len(dc.events) > maxEventLogLength {
+		dc.events = dc.events[:maxEventLogLength]
+	}
+}
+
+// clear resets every counter and the event log, as required by the FC 8
+// Restart Communications Option and Clear Counters and Diagnostic Register
+// sub-functions.
+func (dc *diagnosticCounters) clear() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.busMessageCount = 0
+	dc.busCommErrorCount = 0
+	dc.busExceptionErrorCount = 0
+	dc.serverMessageCount = 0
+	dc.serverNoResponseCount = 0
+	dc.eventCount = 0
+	dc.events = nil
+}
+
+// snapshot returns a consistent copy of the counters, for FC 11/FC 12
+// responses.
+func (dc *diagnosticCounters) snapshot() (busMessageCount, busCommErrorCount, busExceptionErrorCount,
+	serverMessageCount, serverNoResponseCount, eventCount uint16, events []byte) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	return dc.busMessageCount, dc.busCommErrorCount, dc.busExceptionErrorCount,
+		dc.serverMessageCount, dc.serverNoResponseCount, dc.eventCount, append([]byte{}, dc.events...)
+}
+
+// defaultHandleDiagnostics implements the FC 8 sub-functions real PLC
+// commissioning tools rely on even when the handler doesn't implement
+// DiagnosticsHandler: Return Query Data simply echoes req.Data back, while
+// Restart Communications Option and Clear Counters and Diagnostic Register
+// both reset counters. Any other sub-function is rejected with
+// ErrIllegalFunction, leaving room for DiagnosticsHandler to cover it.
+func defaultHandleDiagnostics(req *DiagnosticsRequest, counters *diagnosticCounters) (res []byte, err error) {
+	switch req.SubFunc {
+	case diagSubReturnQueryData:
+		res = append([]byte{}, req.Data...)
+
+	case diagSubRestartCommunicationsOption, diagSubClearCountersAndDiagnosticRegister:
+		if counters != nil {
+			counters.clear()
+		}
+		res = append([]byte{}, req.Data...)
+
+	default:
+		err = ErrIllegalFunction
+	}
+
+	return
+}
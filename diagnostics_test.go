@@ -0,0 +1,69 @@
+package modbus
+
+import "testing"
+
+func TestDiagnosticCountersRecordAndClear(t *testing.T) {
+	dc := &diagnosticCounters{}
+
+	dc.recordFrame()
+	dc.recordFrame()
+	dc.recordCommError()
+
+	busMessageCount, busCommErrorCount, _, serverMessageCount, _, _, _ := dc.snapshot()
+	if busMessageCount != 3 {
+		t.Fatalf("unexpected bus message count: got %v, want 3", busMessageCount)
+	}
+	if busCommErrorCount != 1 {
+		t.Fatalf("unexpected bus comm error count: got %v, want 1", busCommErrorCount)
+	}
+	if serverMessageCount != 2 {
+		t.Fatalf("unexpected server message count: got %v, want 2", serverMessageCount)
+	}
+
+	dc.clear()
+
+	busMessageCount, busCommErrorCount, _, serverMessageCount, _, eventCount, events := dc.snapshot()
+	if busMessageCount != 0 || busCommErrorCount != 0 || serverMessageCount != 0 || eventCount != 0 || len(events) != 0 {
+		t.Fatalf("expected all counters and the event log to be reset after clear()")
+	}
+}
+
+func TestDefaultHandleDiagnosticsReturnQueryData(t *testing.T) {
+	res, err := defaultHandleDiagnostics(&DiagnosticsRequest{
+		SubFunc: diagSubReturnQueryData,
+		Data:    []byte{0xca, 0xfe},
+	}, nil)
+	if err != nil {
+		t.Fatalf("defaultHandleDiagnostics() returned error: %v", err)
+	}
+	if string(res) != string([]byte{0xca, 0xfe}) {
+		t.Fatalf("expected Return Query Data to echo the payload, got %#v", res)
+	}
+}
+
+func TestDefaultHandleDiagnosticsClearCounters(t *testing.T) {
+	dc := &diagnosticCounters{}
+	dc.recordFrame()
+	dc.recordCommError()
+
+	_, err := defaultHandleDiagnostics(&DiagnosticsRequest{
+		SubFunc: diagSubClearCountersAndDiagnosticRegister,
+	}, dc)
+	if err != nil {
+		t.Fatalf("defaultHandleDiagnostics() returned error: %v", err)
+	}
+
+	busMessageCount, busCommErrorCount, _, _, _, _, _ := dc.snapshot()
+	if busMessageCount != 0 || busCommErrorCount != 0 {
+		t.Fatalf("expected Clear Counters to reset the bus counters")
+	}
+}
+
+func TestDefaultHandleDiagnosticsUnsupportedSubFunc(t *testing.T) {
+	_, err := defaultHandleDiagnostics(&DiagnosticsRequest{
+		SubFunc: 0x00ff,
+	}, nil)
+	if err != ErrIllegalFunction {
+		t.Fatalf("expected ErrIllegalFunction for an unsupported sub-function, got %v", err)
+	}
+}
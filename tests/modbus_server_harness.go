@@ -1,6 +1,9 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
 	"flag"
 	"log"
 	"os"
@@ -11,6 +14,19 @@ import (
 	"github.com/simonvetter/modbus"
 )
 
+// roleOID is the X.509 certificate extension OID carrying the Modbus/TCP
+// Security role, as defined by the Modbus-IDA security specification.
+var roleOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 50316, 802, 1}
+
+// roleReadOnlyFunctionCodes lists the function codes a "read-only" cert role
+// is allowed to invoke.
+var roleReadOnlyFunctionCodes = map[uint8]bool{
+	0x01: true, // read coils
+	0x02: true, // read discrete inputs
+	0x03: true, // read holding registers
+	0x04: true, // read input registers
+}
+
 type harnessHandler struct {
 	lock     sync.Mutex
 	unitID   uint8
@@ -98,6 +114,65 @@ func (h *harnessHandler) HandleHoldingRegisters(req *modbus.HoldingRegistersRequ
 	return
 }
 
+// HandleReadExceptionStatus answers FC 7 by packing the first eight coils
+// into a single status byte, the way commissioning tools expect a quick
+// health/alarm snapshot of the device.
+func (h *harnessHandler) HandleReadExceptionStatus(unitID uint8) (status uint8, err error) {
+	if unitID != h.unitID {
+		return 0, modbus.ErrIllegalFunction
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i := 0; i < 8 && i < len(h.coils); i++ {
+		if h.coils[i] {
+			status |= 1 << uint(i)
+		}
+	}
+
+	return
+}
+
+// HandleAuthorization enforces role-based access control for Modbus/TCP
+// Security connections: it reads the role carried by the peer certificate's
+// Role-OID extension and rejects function codes that role isn't allowed to
+// invoke. Handlers that don't care about per-role restrictions can simply
+// omit this method, in which case any authenticated client is authorized.
+func (h *harnessHandler) HandleAuthorization(unitID uint8, fc uint8, peer *x509.Certificate) error {
+	if unitID != h.unitID {
+		return modbus.ErrIllegalFunction
+	}
+
+	role, err := certRole(peer)
+	if err != nil {
+		return err
+	}
+
+	if role == "read-only" && !roleReadOnlyFunctionCodes[fc] {
+		return modbus.ErrIllegalFunction
+	}
+
+	return nil
+}
+
+// certRole extracts the Modbus/TCP Security role carried by peer's Role-OID
+// extension.
+func certRole(peer *x509.Certificate) (role string, err error) {
+	for _, ext := range peer.Extensions {
+		if !ext.Id.Equal(roleOID) {
+			continue
+		}
+
+		_, err = asn1.Unmarshal(ext.Value, &role)
+		return
+	}
+
+	err = modbus.ErrIllegalFunction
+
+	return
+}
+
 func (h *harnessHandler) HandleInputRegisters(req *modbus.InputRegistersRequest) (res []uint16, err error) {
 	if req.UnitId != h.unitID {
 		return nil, modbus.ErrIllegalFunction
@@ -117,13 +192,17 @@ func (h *harnessHandler) HandleInputRegisters(req *modbus.InputRegistersRequest)
 }
 
 func main() {
-	mode := flag.String("mode", "tcp", "server mode: tcp|rtu|ascii")
-	listen := flag.String("listen", "127.0.0.1:1502", "listen address for tcp mode")
+	mode := flag.String("mode", "tcp", "server mode: tcp|rtu|ascii|tcps")
+	listen := flag.String("listen", "127.0.0.1:1502", "listen address for tcp/tcps modes")
 	serial := flag.String("serial", "", "serial device path for rtu/ascii modes")
 	unitID := flag.Uint("unit-id", 1, "unit id accepted by the harness")
+	serverCert := flag.String("tls-cert", "", "server certificate path for tcps mode")
+	serverKey := flag.String("tls-key", "", "server private key path for tcps mode")
+	clientCA := flag.String("tls-client-ca", "", "CA bundle used to verify client certificates in tcps mode")
 	flag.Parse()
 
 	var url string
+	var tlsConfig *tls.Config
 	switch *mode {
 	case "tcp":
 		url = "tcp://" + *listen
@@ -137,17 +216,24 @@ func main() {
 			log.Fatal("--serial is required for ascii mode")
 		}
 		url = "ascii://" + *serial
+	case "tcps":
+		if *serverCert == "" || *serverKey == "" || *clientCA == "" {
+			log.Fatal("--tls-cert, --tls-key and --tls-client-ca are required for tcps mode")
+		}
+		url = "tcps://" + *listen
+		tlsConfig = loadServerTLSConfig(*serverCert, *serverKey, *clientCA)
 	default:
 		log.Fatalf("unsupported mode: %s", *mode)
 	}
 
 	handler := newHarnessHandler(uint8(*unitID))
 	server, err := modbus.NewServer(&modbus.ServerConfiguration{
-		URL:      url,
-		Speed:    19200,
-		DataBits: 8,
-		Parity:   modbus.PARITY_NONE,
-		StopBits: 2,
+		URL:             url,
+		Speed:           19200,
+		DataBits:        8,
+		Parity:          modbus.PARITY_NONE,
+		StopBits:        2,
+		TLSServerConfig: tlsConfig,
 	}, handler)
 	if err != nil {
 		log.Fatalf("new server failed: %v", err)
@@ -161,3 +247,30 @@ func main() {
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 }
+
+// loadServerTLSConfig builds the *tls.Config passed as
+// ServerConfiguration.TLSServerConfig in tcps mode: it loads the harness's
+// own certificate/key pair and trusts clientCAPath to verify peer
+// certificates, as required by the Modbus/TCP Security profile.
+func loadServerTLSConfig(certPath, keyPath, clientCAPath string) *tls.Config {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		log.Fatalf("failed to load server certificate/key: %v", err)
+	}
+
+	caPEM, err := os.ReadFile(clientCAPath)
+	if err != nil {
+		log.Fatalf("failed to read client CA bundle: %v", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		log.Fatalf("failed to parse client CA bundle: %s", clientCAPath)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+}
@@ -0,0 +1,40 @@
+package modbus
+
+import "context"
+
+// Dir indicates the direction of a traced frame: outbound (Tx) from this end
+// of the link, or inbound (Rx) from the peer.
+type Dir int
+
+const (
+	DirTx Dir = iota
+	DirRx
+)
+
+// TransportTracer is the integration point for wire-level observability,
+// capture/replay and fault injection. Every transport (asciiTransport,
+// rtuTransport, tcpTransport) consults the tracer installed on it, if any,
+// around its Write/Read/readASCIIFrame/assembleASCIIFrame calls. A nil
+// TransportTracer, the default, is a no-op.
+//
+// Tracer is exposed on ClientConfiguration/ServerConfiguration and applied
+// to whichever transport NewClient/NewServer builds for the configured URL.
+type TransportTracer interface {
+	// OnFrameTx is called with the raw bytes and decoded PDU of a frame
+	// right after it has been written to the link.
+	OnFrameTx(dir Dir, raw []byte, p *pdu)
+	// OnFrameRx is called with the raw bytes and decoded PDU of a frame
+	// right after it has been read off the link. err is set when framing
+	// or checksum validation failed, in which case p is nil.
+	OnFrameRx(dir Dir, raw []byte, p *pdu, err error)
+	// OnError is called whenever reading or decoding a frame fails
+	// (framing, checksum, timeout...), right before the error is returned
+	// to the caller. Errors from writing a frame or from setting the link
+	// deadline are returned without being reported here.
+	OnError(err error)
+	// InjectRx is consulted right after a transport has read a complete
+	// frame off the link and before it parses it. If ok is true, injected
+	// replaces raw, allowing deterministic fault injection (corrupted
+	// checksums, truncated frames...) without touching the link itself.
+	InjectRx(ctx context.Context, raw []byte) (injected []byte, ok bool)
+}
@@ -0,0 +1,159 @@
+package modbus
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// PcapTracer is a TransportTracer that writes every frame it sees to w as a
+// timestamped, direction-tagged hex record, one per line. It's meant for
+// capturing known-good runs so later captures can be diffed against them,
+// e.g. with the pipe-based tests already in this package.
+type PcapTracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewPcapTracer returns a PcapTracer writing its records to w.
+func NewPcapTracer(w io.Writer) *PcapTracer {
+	return &PcapTracer{w: w}
+}
+
+func (pt *PcapTracer) OnFrameTx(dir Dir, raw []byte, p *pdu) {
+	pt.record(dir, raw)
+}
+
+func (pt *PcapTracer) OnFrameRx(dir Dir, raw []byte, p *pdu, err error) {
+	pt.record(dir, raw)
+}
+
+func (pt *PcapTracer) OnError(err error) {
+}
+
+func (pt *PcapTracer) InjectRx(ctx context.Context, raw []byte) (injected []byte, ok bool) {
+	return
+}
+
+func (pt *PcapTracer) record(dir Dir, raw []byte) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	fmt.Fprintf(pt.w, "%d %s %s\n", time.Now().UnixNano(), dirLabel(dir), hex.EncodeToString(raw))
+}
+
+func dirLabel(dir Dir) string {
+	if dir == DirTx {
+		return "tx"
+	}
+
+	return "rx"
+}
+
+// ChaosMode selects the kind of corruption ChaosTracer applies.
+type ChaosMode int
+
+const (
+	// ChaosCorruptChecksum flips a bit in the frame's LRC byte, exercising
+	// the ErrBadLRC recovery path.
+	ChaosCorruptChecksum ChaosMode = iota
+	// ChaosTruncate cuts the frame short, exercising the ErrShortFrame
+	// recovery path.
+	ChaosTruncate
+	// ChaosDropFrame discards the frame entirely, exercising the
+	// ErrProtocolError recovery path.
+	ChaosDropFrame
+)
+
+// ChaosTracer is a TransportTracer that deterministically corrupts every Nth
+// received frame according to Mode, so that the ErrBadLRC/ErrShortFrame/
+// ErrProtocolError recovery paths can be exercised in tests without relying
+// on a flaky or hand-crafted malformed frame.
+//
+// Its InjectRx hook works on the ':'...<LRC>\r\n ASCII framing assembled by
+// asciiTransport.readASCIIFrame, the only caller of InjectRx today; it isn't
+// meant to be installed on a binary RTU/CRC transport.
+type ChaosTracer struct {
+	// Mode selects how a targeted frame is corrupted.
+	Mode ChaosMode
+	// Every is the corruption period: the tracer corrupts the Every-th,
+	// (2*Every)th, (3*Every)th... frame it sees. Every <= 0 disables
+	// corruption entirely.
+	Every int
+
+	mu    sync.Mutex
+	count int
+}
+
+func (ct *ChaosTracer) OnFrameTx(dir Dir, raw []byte, p *pdu) {
+}
+
+func (ct *ChaosTracer) OnFrameRx(dir Dir, raw []byte, p *pdu, err error) {
+}
+
+func (ct *ChaosTracer) OnError(err error) {
+}
+
+func (ct *ChaosTracer) InjectRx(ctx context.Context, raw []byte) (injected []byte, ok bool) {
+	if ct.Every <= 0 {
+		return
+	}
+
+	ct.mu.Lock()
+	ct.count++
+	n := ct.count
+	ct.mu.Unlock()
+
+	if n%ct.Every != 0 {
+		return
+	}
+
+	switch ct.Mode {
+	case ChaosCorruptChecksum:
+		injected, ok = corruptChecksum(raw)
+	case ChaosTruncate:
+		injected, ok = truncateFrame(raw)
+	case ChaosDropFrame:
+		injected, ok = []byte{}, true
+	}
+
+	return
+}
+
+// corruptChecksum flips a bit in the decoded value of the frame's trailing
+// LRC byte and re-encodes it as hex, so the frame remains valid ASCII hex
+// framing but fails verifyLRC. raw is expected to be a complete
+// ':'...<LRC>\r\n frame as assembled by asciiTransport.readASCIIFrame: the
+// LRC's two hex digits are the two bytes right before the trailing CRLF.
+func corruptChecksum(raw []byte) (corrupted []byte, ok bool) {
+	if len(raw) < 4 {
+		return
+	}
+
+	lrcHex := raw[len(raw)-4 : len(raw)-2]
+
+	decoded := make([]byte, 1)
+	if _, err := hex.Decode(decoded, lrcHex); err != nil {
+		return
+	}
+	decoded[0] ^= 0x01
+
+	corrupted = append([]byte{}, raw...)
+	copy(corrupted[len(corrupted)-4:len(corrupted)-2], toHexUpper(decoded[0]))
+
+	return corrupted, true
+}
+
+// truncateFrame keeps the leading ':' and a single hex digit of the payload
+// but otherwise reattaches the trailing CRLF, producing a well-formed-looking
+// but far too short frame.
+func truncateFrame(raw []byte) (truncated []byte, ok bool) {
+	if len(raw) < 4 {
+		return
+	}
+
+	return []byte{raw[0], raw[1], '\r', '\n'}, true
+}
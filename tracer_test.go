@@ -0,0 +1,94 @@
+package modbus
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChaosTracerCorruptChecksumTriggersErrBadLRC(t *testing.T) {
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	at := newASCIITransport(p2, "", 9600, 50*time.Millisecond, nil)
+	at.SetTracer(&ChaosTracer{Mode: ChaosCorruptChecksum, Every: 1})
+
+	frame := []byte(":31030411223362\r\n")
+
+	go func() {
+		p1.Write(frame)
+	}()
+
+	at.link.SetDeadline(time.Now().Add(100 * time.Millisecond))
+
+	_, err := at.readASCIIFrame()
+	if err != ErrBadLRC {
+		t.Fatalf("expected ErrBadLRC, got %v", err)
+	}
+}
+
+func TestChaosTracerTruncateTriggersErrShortFrame(t *testing.T) {
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	at := newASCIITransport(p2, "", 9600, 50*time.Millisecond, nil)
+	at.SetTracer(&ChaosTracer{Mode: ChaosTruncate, Every: 1})
+
+	frame := []byte(":31030411223362\r\n")
+
+	go func() {
+		p1.Write(frame)
+	}()
+
+	at.link.SetDeadline(time.Now().Add(100 * time.Millisecond))
+
+	_, err := at.readASCIIFrame()
+	if err != ErrShortFrame {
+		t.Fatalf("expected ErrShortFrame, got %v", err)
+	}
+}
+
+func TestChaosTracerEveryNSkipsUntargetedFrames(t *testing.T) {
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	at := newASCIITransport(p2, "", 9600, 50*time.Millisecond, nil)
+	at.SetTracer(&ChaosTracer{Mode: ChaosCorruptChecksum, Every: 2})
+
+	frame := []byte(":31030411223362\r\n")
+
+	go func() {
+		p1.Write(frame)
+	}()
+
+	at.link.SetDeadline(time.Now().Add(100 * time.Millisecond))
+
+	_, err := at.readASCIIFrame()
+	if err != nil {
+		t.Fatalf("expected the first frame to pass through untouched, got %v", err)
+	}
+}
+
+func TestPcapTracerRecordsFramesWithDirection(t *testing.T) {
+	var buf bytes.Buffer
+	pt := NewPcapTracer(&buf)
+
+	pt.OnFrameTx(DirTx, []byte{0x01, 0x02}, nil)
+	pt.OnFrameRx(DirRx, []byte{0x03, 0x04}, nil, nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], " tx ") || !strings.HasSuffix(lines[0], "0102") {
+		t.Fatalf("unexpected tx record: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], " rx ") || !strings.HasSuffix(lines[1], "0304") {
+		t.Fatalf("unexpected rx record: %q", lines[1])
+	}
+}
@@ -2,13 +2,15 @@ package modbus
 
 type transportType uint
 const (
-	modbusRTU        transportType   = 1
-	modbusRTUOverTCP transportType   = 2
-	modbusRTUOverUDP transportType   = 3
-	modbusASCII      transportType   = 4
-	modbusTCP        transportType   = 5
-	modbusTCPOverTLS transportType   = 6
-	modbusTCPOverUDP transportType   = 7
+	modbusRTU          transportType   = 1
+	modbusRTUOverTCP   transportType   = 2
+	modbusRTUOverUDP   transportType   = 3
+	modbusASCII        transportType   = 4
+	modbusTCP          transportType   = 5
+	modbusTCPOverTLS   transportType   = 6
+	modbusTCPOverUDP   transportType   = 7
+	modbusASCIIOverTCP transportType   = 8
+	modbusASCIIOverUDP transportType   = 9
 )
 
 type transport interface {